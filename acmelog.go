@@ -0,0 +1,60 @@
+package easyhttpserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// acmeChallengePrefix is the path autocert.Manager.HTTPHandler serves http-01
+// challenge responses under.
+const acmeChallengePrefix = "/.well-known/acme-challenge/"
+
+// wrapACMEChallengeLogging emits EventACMEChallengeServed whenever a request
+// hits the ACME http-01 challenge path, then delegates to handler as usual.
+func wrapACMEChallengeLogging(handler http.Handler, log Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, acmeChallengePrefix) {
+			log.Event(EventACMEChallengeServed, map[string]interface{}{
+				"host": r.Host,
+				"path": r.URL.Path,
+			})
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// loggingCache wraps an autocert.Cache, emitting EventACMECertObtained or
+// EventACMECertRenewed whenever a certificate is stored, by checking whether
+// a value was already present under the same key. autocert doesn't document
+// its cache key format, so this is a best-effort approximation: it skips
+// keys that look like the account key or an http-01 token rather than a
+// certificate.
+type loggingCache struct {
+	autocert.Cache
+	log Logger
+}
+
+func (c *loggingCache) Put(ctx context.Context, key string, data []byte) error {
+	_, getErr := c.Cache.Get(ctx, key)
+	existed := getErr == nil
+
+	if err := c.Cache.Put(ctx, key, data); err != nil {
+		return err
+	}
+
+	if looksLikeCertKey(key) {
+		event := EventACMECertObtained
+		if existed {
+			event = EventACMECertRenewed
+		}
+		c.log.Event(event, map[string]interface{}{"key": key})
+	}
+	return nil
+}
+
+func looksLikeCertKey(key string) bool {
+	return key != "acme_account+key" && !strings.HasSuffix(key, "+token")
+}
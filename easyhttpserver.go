@@ -2,12 +2,15 @@ package easyhttpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/acme"
@@ -19,8 +22,11 @@ import (
 type Options struct {
 	// App settings
 
-	// Log is a log.Printf-style function to output logs
-	Log func(format string, v ...interface{})
+	// Log receives informational/warning/error messages and structured
+	// lifecycle events from the server. Defaults to a no-op logger; use
+	// LogPrintf, LogSlog or LogZap to adapt an existing logger, or implement
+	// Logger directly.
+	Log Logger
 	// DefaultDevPort is the HTTP port to use when running on localhost.
 	DefaultDevPort int
 	// GracefulShutdownTimeout is the time to allow existing requests to complete
@@ -30,7 +36,8 @@ type Options struct {
 
 	// Env settings
 
-	// Port sets the HTTP port to use. Let's Encrypt mode requires port 80.
+	// Port sets the HTTP port to use. Deprecated in favor of HTTPPort, which
+	// defaults to Port when left unset.
 	Port int
 	// Host sets the domain name to provide HTTPS certificates for.
 	Host string
@@ -43,6 +50,101 @@ type Options struct {
 	// Let's Encrypt might send important notifications to this email.
 	LetsEncryptEmail string
 
+	// CertCache overrides where Let's Encrypt certificates and keys are
+	// stored. Defaults to autocert.DirCache(LetsEncryptCacheDir); set this to
+	// use a third-party cache (e.g. backed by Redis or S3) instead.
+	CertCache autocert.Cache
+	// HostPolicy overrides which hosts are allowed to obtain a certificate.
+	// Defaults to autocert.HostWhitelist(Host); set this (or the HOSTS
+	// environment variable, via HostPolicyFromList) to serve Let's Encrypt
+	// certificates for multiple domains from one process.
+	HostPolicy autocert.HostPolicy
+
+	// HTTPPort sets the port to listen for plain HTTP on. Defaults to Port,
+	// kept for backwards compatibility. Let's Encrypt mode requires this to
+	// be 80.
+	HTTPPort int
+	// HTTPSPort sets the port to listen for HTTPS on, when HTTPS is enabled
+	// via LetsEncrypt or CertFile/KeyFile. Defaults to 443.
+	HTTPSPort int
+	// HTTPBehavior controls what the plain HTTP listener does once HTTPS is
+	// enabled. Defaults to HTTPServeBoth.
+	HTTPBehavior HTTPBehavior
+
+	// CertFile and KeyFile provide a static TLS certificate and key to serve
+	// HTTPS with, as an alternative to LetsEncrypt. Both must be set together,
+	// and are mutually exclusive with LetsEncrypt.
+	CertFile string
+	KeyFile  string
+
+	// ReadTimeout, ReadHeaderTimeout, WriteTimeout and IdleTimeout configure
+	// the underlying http.Server timeouts. Left at zero, they default to 60s,
+	// 10s, 30s and 120s respectively, since http.Server has no limits at all
+	// out of the box, which is a well-known footgun (slowloris, resource
+	// exhaustion).
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// MaxHeaderBytes limits the size of request headers. Defaults to 1 MB.
+	MaxHeaderBytes int
+	// MaxConnections limits the number of simultaneously open connections per
+	// listener, via golang.org/x/net/netutil.LimitListener. Zero (default)
+	// means unlimited.
+	MaxConnections int
+	// KeepAlivePeriod sets the TCP keep-alive period for accepted
+	// connections. Defaults to 3 minutes, matching net/http's own default.
+	KeepAlivePeriod time.Duration
+	// DisableHTTP2 turns off HTTP/2 support on the HTTPS listener, forcing
+	// HTTP/1.1. HTTP/2 is enabled by default, matching net/http.
+	DisableHTTP2 bool
+	// H2C enables cleartext HTTP/2 on the plain HTTP listener, for use behind
+	// a proxy that terminates TLS but still wants to speak HTTP/2 to this
+	// process. Disabled by default.
+	H2C bool
+
+	// UnixSocket, if set, makes the server listen on a Unix domain socket at
+	// this path, in addition to the TCP port if Port or HTTPPort is set, or
+	// instead of it otherwise. Incompatible with LetsEncrypt mode.
+	UnixSocket string
+	// UnixSocketMode sets the file mode of the Unix domain socket once created.
+	// Defaults to 0660.
+	UnixSocketMode os.FileMode
+	// UnixSocketOwner sets the owner of the Unix domain socket once created,
+	// as "user" or "user:group" (names or numeric ids). Leave empty to keep
+	// the owner that created the process.
+	UnixSocketOwner string
+
+	// Middleware settings
+
+	// Middleware wraps the handler with custom middleware, applied in slice
+	// order (Middleware[0] runs first), innermost of the built-in toggles
+	// below but outside the handler itself.
+	Middleware []func(http.Handler) http.Handler
+	// AccessLog emits a structured EventHTTPRequest event, via Log, for
+	// every request. Disabled by default.
+	AccessLog bool
+	// Recovery recovers panics from the handler chain, logs them via Log,
+	// and responds with 500 Internal Server Error instead of crashing the
+	// process. Disabled by default.
+	Recovery bool
+	// RequestID assigns a request id to every request (reusing the
+	// X-Request-Id request header if the caller already set one), available
+	// via RequestIDFromContext and echoed back in the X-Request-Id response
+	// header. Disabled by default.
+	RequestID bool
+	// RealIP overwrites the request's RemoteAddr with the client address
+	// found in the X-Forwarded-For or X-Real-IP header. Only enable this
+	// behind a trusted reverse proxy, since these headers are otherwise
+	// trivially spoofable. Disabled by default.
+	RealIP bool
+	// HealthPath always responds 200 OK to GET and HEAD requests, for use as
+	// a liveness probe. Defaults to /healthz.
+	HealthPath string
+	// ReadyPath always responds 200 OK to GET and HEAD requests, for use as
+	// a readiness probe. Defaults to /readyz.
+	ReadyPath string
+
 	// Derived settings
 
 	// IsLocalDevelopmentHost signals that Host is a localhost address. In this
@@ -59,12 +161,40 @@ type Server struct {
 	httpServer  *http.Server
 	httpsServer *http.Server
 	errc        <-chan error
+	errCount    int
 
 	gracefulShutdownTimeout time.Duration
 
-	log       func(format string, v ...interface{})
+	log       Logger
 	baseURL   string
 	endpoints []string
+
+	// mu guards the fields below, which Reload mutates in place while the
+	// server is running.
+	mu   sync.Mutex
+	opts Options
+
+	// mgr and certStore are non-nil only when HTTPS is enabled via
+	// LetsEncrypt or CertFile/KeyFile, respectively; Reload uses whichever
+	// one is set to swap in new certificate configuration. mgr.Cache and
+	// mgr.HostPolicy are acmeCache/acmeHostPolicy so Reload can swap them
+	// without racing mgr's own concurrent reads of those fields during
+	// in-flight handshakes.
+	mgr            *autocert.Manager
+	acmeCache      *acmeCache
+	acmeHostPolicy *acmeHostPolicy
+	certStore      *atomicCertificate
+
+	// httpHandlerBox and httpsHandlerBox let Reload swap the active handler
+	// without a data race against in-flight requests.
+	httpHandlerBox  *reloadableHandler
+	httpsHandlerBox *reloadableHandler
+
+	// rawListeners and rawHTTPSListener are the unwrapped listeners (before
+	// wrapListener's keep-alive/connection-limit wrapping), kept around so
+	// ListenerFiles can hand their file descriptors to a replacement process.
+	rawListeners     []net.Listener
+	rawHTTPSListener net.Listener
 }
 
 // LoadEnv reads configuration options from the environment variables.
@@ -84,6 +214,14 @@ func (sopt *Options) LoadEnv() error {
 		sopt.Host = s
 	}
 
+	if s := os.Getenv("LISTEN_UNIX"); s != "" {
+		sopt.UnixSocket = s
+	}
+
+	if s := os.Getenv("HOSTS"); s != "" {
+		sopt.HostPolicy = HostPolicyFromList(s)
+	}
+
 	if s := os.Getenv("LETSENCRYPT_EMAIL"); s != "" {
 		if !strings.Contains(s, "@") {
 			return fmt.Errorf("invalid value of LETSENCRYPT_EMAIL %q: missing @", s)
@@ -113,7 +251,7 @@ func (sopt *Options) Verify() error {
 		sopt.IsLocalDevelopmentHost = true
 	}
 
-	if sopt.Port == 0 {
+	if sopt.Port == 0 && sopt.UnixSocket == "" {
 		if sopt.IsLocalDevelopmentHost {
 			if sopt.DefaultDevPort == 0 {
 				return fmt.Errorf("missing PORT for local development")
@@ -124,6 +262,36 @@ func (sopt *Options) Verify() error {
 		}
 	}
 
+	if sopt.HTTPPort == 0 {
+		sopt.HTTPPort = sopt.Port
+	}
+
+	if (sopt.CertFile == "") != (sopt.KeyFile == "") {
+		return fmt.Errorf("CertFile and KeyFile must be set together")
+	}
+	if sopt.CertFile != "" && sopt.LetsEncrypt {
+		return fmt.Errorf("cannot set CertFile/KeyFile together with LetsEncrypt")
+	}
+
+	httpsEnabled := sopt.LetsEncrypt || sopt.CertFile != ""
+	if httpsEnabled && sopt.HTTPSPort == 0 {
+		sopt.HTTPSPort = 443
+	}
+
+	switch sopt.HTTPBehavior {
+	case HTTPAcmeOnly:
+		if !sopt.LetsEncrypt {
+			return fmt.Errorf("%s requires LetsEncrypt to be enabled", sopt.HTTPBehavior)
+		}
+	case HTTPRedirectToHTTPS, HTTPDisabled:
+		if !httpsEnabled {
+			return fmt.Errorf("%s requires HTTPS to be enabled via LetsEncrypt or CertFile/KeyFile", sopt.HTTPBehavior)
+		}
+	}
+	if sopt.LetsEncrypt && sopt.HTTPBehavior == HTTPDisabled {
+		return fmt.Errorf("Let's Encrypt requires an HTTP listener to serve ACME challenges")
+	}
+
 	if sopt.LetsEncrypt {
 		if sopt.IsLocalDevelopmentHost {
 			return fmt.Errorf("Let's Encrypt is not supported on localhost")
@@ -134,11 +302,14 @@ func (sopt *Options) Verify() error {
 		if sopt.LetsEncryptEmail == "" {
 			return fmt.Errorf("missing LETSENCRYPT_EMAIL when LETSENCRYPT_ENABLED is true")
 		}
-		if sopt.LetsEncryptCacheDir == "" {
-			return fmt.Errorf("missing LETSENCRYPT_CACHE_DIR when LETSENCRYPT_ENABLED is true")
+		if sopt.LetsEncryptCacheDir == "" && sopt.CertCache == nil {
+			return fmt.Errorf("missing LETSENCRYPT_CACHE_DIR when LETSENCRYPT_ENABLED is true (or set CertCache)")
+		}
+		if sopt.HTTPPort != 80 {
+			return fmt.Errorf("Let's Encrypt requires HTTP port to be 80, got port %d instead", sopt.HTTPPort)
 		}
-		if sopt.Port != 80 {
-			return fmt.Errorf("Let's Encrypt requires HTTP port to be 80, got port %d instead", sopt.Port)
+		if sopt.UnixSocket != "" {
+			return fmt.Errorf("Let's Encrypt is not supported together with UnixSocket")
 		}
 	}
 
@@ -184,70 +355,242 @@ func Start(handler http.Handler, sopt Options) (*Server, error) {
 		sopt.GracefulShutdownTimeout = 10 * time.Second
 	}
 
-	errc := make(chan error, 2)
+	if acme.LetsEncryptURL != "https://acme-v02.api.letsencrypt.org/directory" {
+		return nil, fmt.Errorf("ACMEv2 is not supported by this Go build (%s): acme.LetsEncryptURL = %q", runtime.Version(), acme.LetsEncryptURL)
+	}
+
+	sysListeners, err := systemdListeners()
+	if err != nil {
+		return nil, err
+	}
+
+	httpsEnabled := sopt.LetsEncrypt || sopt.CertFile != ""
+	socketActivated := len(sysListeners) > 0
+
+	var httpListeners []net.Listener
+	var httpsListener net.Listener
+	if socketActivated {
+		// Inherited fds follow the same ordering as ListenerFiles/Adopt: the
+		// plain HTTP listeners (Unix socket and/or HTTPPort) first, followed
+		// by the HTTPS listener last if HTTPS is enabled. Without this split,
+		// the fd meant for TLS termination would be fed into the plain HTTP
+		// server instead.
+		if httpsEnabled {
+			httpListeners = sysListeners[:len(sysListeners)-1]
+			httpsListener = sysListeners[len(sysListeners)-1]
+		} else {
+			httpListeners = sysListeners
+		}
+	} else {
+		if sopt.UnixSocket != "" {
+			ln, err := listenUnixSocket(sopt.UnixSocket, sopt.UnixSocketMode, sopt.UnixSocketOwner)
+			if err != nil {
+				return nil, err
+			}
+			httpListeners = append(httpListeners, ln)
+		}
+		if sopt.HTTPPort != 0 && sopt.HTTPBehavior != HTTPDisabled {
+			ln, err := net.Listen("tcp", fmt.Sprintf(":%d", sopt.HTTPPort))
+			if err != nil {
+				return nil, err
+			}
+			httpListeners = append(httpListeners, ln)
+		}
+		if httpsEnabled {
+			ln, err := net.Listen("tcp", fmt.Sprintf(":%d", sopt.HTTPSPort))
+			if err != nil {
+				return nil, err
+			}
+			httpsListener = ln
+		}
+	}
+	if len(httpListeners) == 0 && !httpsEnabled {
+		return nil, fmt.Errorf("no listeners configured: set Port or UnixSocket")
+	}
+
+	srv, err := wireServer(handler, sopt, httpListeners, httpsListener)
+	if err != nil {
+		return nil, err
+	}
+
+	if socketActivated {
+		srv.endpoints = []string{srv.baseURL}
+		for _, ln := range httpListeners {
+			srv.endpoints = append(srv.endpoints, ln.Addr().String()+" (socket-activated)")
+		}
+		if httpsListener != nil {
+			srv.endpoints = append(srv.endpoints, httpsListener.Addr().String()+" (socket-activated)")
+		}
+	}
+
+	return srv, nil
+}
+
+// wireServer builds a Server around already-open listeners, shared between
+// Start (which opens fresh listeners) and Adopt (which reuses listeners
+// inherited from a previous process). handler is wrapped with whichever of
+// LetsEncrypt/CertFile/H2C are configured in sopt.
+func wireServer(handler http.Handler, sopt Options, httpListeners []net.Listener, httpsListener net.Listener) (*Server, error) {
+	if sopt.GracefulShutdownTimeout == 0 {
+		sopt.GracefulShutdownTimeout = 10 * time.Second
+	}
+
+	httpsEnabled := sopt.LetsEncrypt || sopt.CertFile != ""
+
+	baseURL := sopt.BaseURL()
+	if sopt.HTTPPort == 0 && sopt.UnixSocket != "" {
+		baseURL = "unix://" + sopt.UnixSocket
+	}
+
+	log := sopt.Log
+	if log == nil {
+		log = noopLogger{}
+	}
+	handler = sopt.wrapMiddleware(handler, log)
+
+	errc := make(chan error, len(httpListeners)+1)
 	srv := &Server{
 		errc:                    errc,
 		gracefulShutdownTimeout: sopt.GracefulShutdownTimeout,
-		baseURL:                 sopt.BaseURL(),
+		baseURL:                 baseURL,
+		opts:                    sopt,
+		rawListeners:            httpListeners,
+		rawHTTPSListener:        httpsListener,
+		log:                     log,
 	}
 
 	httpHandler := handler
 
-	if acme.LetsEncryptURL != "https://acme-v02.api.letsencrypt.org/directory" {
-		return nil, fmt.Errorf("ACMEv2 is not supported by this Go build (%s): acme.LetsEncryptURL = %q", runtime.Version(), acme.LetsEncryptURL)
-	}
-
-	if sopt.LetsEncrypt {
-		info, err := os.Stat(sopt.LetsEncryptCacheDir)
-		if err != nil {
-			return nil, fmt.Errorf("cannot access Let's Encrypt cache dir %q: %w", sopt.LetsEncryptCacheDir, err)
+	switch {
+	case sopt.LetsEncrypt:
+		cache := sopt.CertCache
+		if cache == nil {
+			info, err := os.Stat(sopt.LetsEncryptCacheDir)
+			if err != nil {
+				return nil, fmt.Errorf("cannot access Let's Encrypt cache dir %q: %w", sopt.LetsEncryptCacheDir, err)
+			}
+			if !info.IsDir() {
+				return nil, fmt.Errorf("Let's Encrypt cache dir %q is not a directory", sopt.LetsEncryptCacheDir)
+			}
+			cache = autocert.DirCache(sopt.LetsEncryptCacheDir)
 		}
-		if !info.IsDir() {
-			return nil, fmt.Errorf("Let's Encrypt cache dir %q is not a directory", sopt.LetsEncryptCacheDir)
+		cache = &loggingCache{Cache: cache, log: log}
+
+		hostPolicy := sopt.HostPolicy
+		if hostPolicy == nil {
+			hostPolicy = autocert.HostWhitelist(sopt.Host)
 		}
 
-		mgr := &autocert.Manager{
+		srv.acmeCache = newACMECache(cache)
+		srv.acmeHostPolicy = newACMEHostPolicy(hostPolicy)
+		srv.mgr = &autocert.Manager{
 			Prompt:     autocert.AcceptTOS,
-			Cache:      autocert.DirCache(sopt.LetsEncryptCacheDir),
-			HostPolicy: autocert.HostWhitelist(sopt.Host),
+			Cache:      srv.acmeCache,
+			HostPolicy: srv.acmeHostPolicy.policy,
 			Email:      sopt.LetsEncryptEmail,
 			// Client: &acme.Client{
 			// 	DirectoryURL: ,
 			// },
 		}
 
+		srv.httpsHandlerBox = newReloadableHandler(handler)
 		srv.httpsServer = &http.Server{
-			Addr:      ":https",
-			Handler:   handler,
-			TLSConfig: mgr.TLSConfig(),
+			Handler:   srv.httpsHandlerBox,
+			TLSConfig: srv.mgr.TLSConfig(),
+		}
+		sopt.applyTimeouts(srv.httpsServer)
+		if sopt.DisableHTTP2 {
+			disableHTTP2(srv.httpsServer)
 		}
 
-		httpHandler = mgr.HTTPHandler(nil)
+		var fallback http.Handler
+		if sopt.HTTPBehavior != HTTPRedirectToHTTPS {
+			fallback = httpFallbackHandler(sopt.HTTPBehavior, handler, sopt)
+		}
+		httpHandler = wrapACMEChallengeLogging(srv.mgr.HTTPHandler(fallback), log)
 
+		srv.errCount++
+		httpsAddr := httpsListener.Addr().String()
+		log.Event(EventListenerStarted, map[string]interface{}{"addr": httpsAddr})
 		go func() {
-			err := srv.httpsServer.ListenAndServeTLS("", "")
+			err := srv.httpsServer.ServeTLS(sopt.wrapListener(httpsListener), "", "")
 			if err == http.ErrServerClosed {
 				err = nil
 			}
+			if err != nil {
+				log.Event(EventListenerFailed, map[string]interface{}{"addr": httpsAddr, "error": err.Error()})
+			}
 			errc <- err
 		}()
+
+	case sopt.CertFile != "":
+		srv.certStore = &atomicCertificate{}
+		if err := srv.certStore.load(sopt.CertFile, sopt.KeyFile); err != nil {
+			return nil, err
+		}
+
+		srv.httpsHandlerBox = newReloadableHandler(handler)
+		srv.httpsServer = &http.Server{
+			Handler:   srv.httpsHandlerBox,
+			TLSConfig: &tls.Config{GetCertificate: srv.certStore.getCertificate},
+		}
+		sopt.applyTimeouts(srv.httpsServer)
+		if sopt.DisableHTTP2 {
+			disableHTTP2(srv.httpsServer)
+		}
+
+		httpHandler = httpFallbackHandler(sopt.HTTPBehavior, handler, sopt)
+
+		srv.errCount++
+		httpsAddr := httpsListener.Addr().String()
+		log.Event(EventListenerStarted, map[string]interface{}{"addr": httpsAddr})
+		go func() {
+			err := srv.httpsServer.ServeTLS(sopt.wrapListener(httpsListener), "", "")
+			if err == http.ErrServerClosed {
+				err = nil
+			}
+			if err != nil {
+				log.Event(EventListenerFailed, map[string]interface{}{"addr": httpsAddr, "error": err.Error()})
+			}
+			errc <- err
+		}()
+	}
+
+	if sopt.H2C {
+		httpHandler = wrapH2C(httpHandler)
 	}
 
+	srv.httpHandlerBox = newReloadableHandler(httpHandler)
 	srv.httpServer = &http.Server{
-		Addr:    fmt.Sprintf(":%d", sopt.Port),
-		Handler: httpHandler,
+		Handler: srv.httpHandlerBox,
+	}
+	sopt.applyTimeouts(srv.httpServer)
+	for _, ln := range httpListeners {
+		ln := sopt.wrapListener(ln)
+		srv.errCount++
+		addr := ln.Addr().String()
+		log.Event(EventListenerStarted, map[string]interface{}{"addr": addr})
+		go func() {
+			err := srv.httpServer.Serve(ln)
+			if err == http.ErrServerClosed {
+				err = nil
+			}
+			if err != nil {
+				log.Event(EventListenerFailed, map[string]interface{}{"addr": addr, "error": err.Error()})
+			}
+			errc <- err
+		}()
 	}
-	go func() {
-		err := srv.httpServer.ListenAndServe()
-		if err == http.ErrServerClosed {
-			err = nil
-		}
-		errc <- err
-	}()
 
-	srv.endpoints = append(srv.endpoints, sopt.BaseURL())
-	if !sopt.IsLocalDevelopmentHost && sopt.Port != 80 {
-		srv.endpoints = append(srv.endpoints, fmt.Sprintf("127.0.0.1:%d", sopt.Port))
+	srv.endpoints = append(srv.endpoints, baseURL)
+	if !sopt.IsLocalDevelopmentHost && sopt.HTTPPort != 0 && sopt.HTTPPort != 80 {
+		srv.endpoints = append(srv.endpoints, fmt.Sprintf("127.0.0.1:%d", sopt.HTTPPort))
+	}
+	if sopt.UnixSocket != "" && sopt.HTTPPort != 0 {
+		srv.endpoints = append(srv.endpoints, "unix:"+sopt.UnixSocket)
+	}
+	if httpsEnabled && sopt.HTTPSPort != 443 {
+		srv.endpoints = append(srv.endpoints, fmt.Sprintf("https://%s:%d", sopt.Host, sopt.HTTPSPort))
 	}
 
 	return srv, nil
@@ -274,26 +617,19 @@ func (srv *Server) BaseURL() string {
 // or there's an error accepting connections).
 func (srv *Server) Wait() error {
 	var err error
-	if srv.httpServer != nil {
-		e := <-srv.errc
-		if err == nil {
-			err = e
-		}
-	}
-	if srv.httpsServer != nil {
+	for i := 0; i < srv.errCount; i++ {
 		e := <-srv.errc
 		if err == nil {
 			err = e
 		}
 	}
-
 	return err
 }
 
 // Shutdown stops accepting new connections, then waits for GracefulShutdownTimeout
 // for existing requests to be finished, and then forcefully closes all connections.
 func (srv *Server) Shutdown() {
-	gracefulShutdown(srv.Log, srv.gracefulShutdownTimeout, func(ctx context.Context) error {
+	gracefulShutdown(srv.log, srv.gracefulShutdownTimeout, func(ctx context.Context) error {
 		err := srv.httpServer.Shutdown(ctx)
 		if srv.httpsServer != nil {
 			err2 := srv.httpsServer.Shutdown(ctx)
@@ -312,7 +648,7 @@ func (srv *Server) Shutdown() {
 
 // gracefulShutdown tries to do a graceful shutdown, but abandons the attempt and
 // performs a forceful shutdown after a timeout.
-func gracefulShutdown(log func(format string, v ...interface{}), gracePeriod time.Duration, graceful func(ctx context.Context) error, forceful func()) {
+func gracefulShutdown(log Logger, gracePeriod time.Duration, graceful func(ctx context.Context) error, forceful func()) {
 	defer forceful()
 
 	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
@@ -320,7 +656,9 @@ func gracefulShutdown(log func(format string, v ...interface{}), gracePeriod tim
 
 	err := graceful(ctx)
 	if err == context.DeadlineExceeded {
-		log("graceful shutdown timed out, will close connections forcibly")
+		log.Warnf("graceful shutdown timed out, will close connections forcibly")
+		log.Event(EventShutdownGracefulTimeout, nil)
+		log.Event(EventShutdownForced, nil)
 	} else if err != nil {
 		panic(err)
 	}
@@ -337,9 +675,7 @@ func parseBool(s string) (v bool, ok bool) {
 	}
 }
 
-// Log logs to the opt.Log function provided when starting the server.
+// Log logs an informational message via the Logger provided in Options.
 func (srv *Server) Log(format string, args ...interface{}) {
-	if srv.log != nil {
-		srv.log(format, args...)
-	}
+	srv.log.Infof(format, args...)
 }
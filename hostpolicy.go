@@ -0,0 +1,46 @@
+package easyhttpserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// HostPolicyFromList builds an autocert.HostPolicy from a comma-separated
+// list of hosts, such as the HOSTS environment variable. An entry starting
+// with "*." or "." matches any subdomain of that domain, but not the bare
+// domain itself; any other entry must match exactly.
+func HostPolicyFromList(hosts string) autocert.HostPolicy {
+	var exact []string
+	var suffixes []string
+
+	for _, h := range strings.Split(hosts, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		if strings.HasPrefix(h, "*.") {
+			suffixes = append(suffixes, h[1:]) // keep the leading dot
+		} else if strings.HasPrefix(h, ".") {
+			suffixes = append(suffixes, h)
+		} else {
+			exact = append(exact, h)
+		}
+	}
+
+	return func(ctx context.Context, host string) error {
+		for _, h := range exact {
+			if h == host {
+				return nil
+			}
+		}
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(host, suffix) {
+				return nil
+			}
+		}
+		return fmt.Errorf("easyhttpserver: host %q not configured in HOSTS", host)
+	}
+}
@@ -0,0 +1,37 @@
+package easyhttpserver
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHostPolicyFromList(t *testing.T) {
+	policy := HostPolicyFromList("example.com, *.example.org, .example.net")
+
+	cases := []struct {
+		host string
+		ok   bool
+	}{
+		{"example.com", true},
+		{"www.example.com", false}, // exact entries don't match subdomains
+		{"example.org", false},     // "*." requires a subdomain, not the bare host
+		{"api.example.org", true},
+		{"example.net", false}, // a leading "." only matches subdomains, not the bare host
+		{"api.example.net", true},
+		{"other.com", false},
+	}
+
+	for _, c := range cases {
+		err := policy(context.Background(), c.host)
+		if ok := err == nil; ok != c.ok {
+			t.Errorf("policy(%q) allowed = %v, want %v (err: %v)", c.host, ok, c.ok, err)
+		}
+	}
+}
+
+func TestHostPolicyFromListEmpty(t *testing.T) {
+	policy := HostPolicyFromList("")
+	if err := policy(context.Background(), "example.com"); err == nil {
+		t.Error("empty host list should reject every host")
+	}
+}
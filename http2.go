@@ -0,0 +1,22 @@
+package easyhttpserver
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// disableHTTP2 turns off net/http's automatic HTTP/2 support for srv's TLS
+// listener, forcing HTTP/1.1 even when the client negotiates h2 via ALPN.
+func disableHTTP2(srv *http.Server) {
+	srv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+}
+
+// wrapH2C adds cleartext HTTP/2 (h2c) support to a plain HTTP handler, for
+// deployments where a proxy terminates TLS but still speaks HTTP/2 to this
+// process over plain TCP.
+func wrapH2C(handler http.Handler) http.Handler {
+	return h2c.NewHandler(handler, &http2.Server{})
+}
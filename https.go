@@ -0,0 +1,73 @@
+package easyhttpserver
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// HTTPBehavior controls what the plain HTTP listener does once HTTPS is
+// enabled (via LetsEncrypt or CertFile/KeyFile).
+type HTTPBehavior int
+
+const (
+	// HTTPServeBoth serves the handler directly on both HTTP and HTTPS. This
+	// is the default, and the only behavior available when HTTPS is disabled.
+	HTTPServeBoth HTTPBehavior = iota
+	// HTTPRedirectToHTTPS redirects all plain HTTP requests to the HTTPS
+	// equivalent URL. Requires HTTPS to be enabled.
+	HTTPRedirectToHTTPS
+	// HTTPDisabled does not listen for plain HTTP at all. Incompatible with
+	// LetsEncrypt, which needs an HTTP listener to serve ACME challenges.
+	HTTPDisabled
+	// HTTPAcmeOnly serves only ACME http-01 challenge responses on HTTP and
+	// responds to everything else with 404. Requires LetsEncrypt.
+	HTTPAcmeOnly
+)
+
+func (b HTTPBehavior) String() string {
+	switch b {
+	case HTTPServeBoth:
+		return "HTTPServeBoth"
+	case HTTPRedirectToHTTPS:
+		return "HTTPRedirectToHTTPS"
+	case HTTPDisabled:
+		return "HTTPDisabled"
+	case HTTPAcmeOnly:
+		return "HTTPAcmeOnly"
+	default:
+		return fmt.Sprintf("HTTPBehavior(%d)", int(b))
+	}
+}
+
+// httpFallbackHandler returns the handler that should serve plain HTTP
+// requests (outside of ACME challenges, which are handled separately by
+// autocert when Let's Encrypt is enabled), according to behavior.
+func httpFallbackHandler(behavior HTTPBehavior, handler http.Handler, sopt Options) http.Handler {
+	switch behavior {
+	case HTTPAcmeOnly:
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+	case HTTPRedirectToHTTPS:
+		return redirectToHTTPSHandler(sopt)
+	default: // HTTPServeBoth
+		return handler
+	}
+}
+
+// redirectToHTTPSHandler redirects every request to its HTTPS equivalent,
+// using sopt.HTTPSPort instead of the request's own port.
+func redirectToHTTPSHandler(sopt Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		target := "https://" + host
+		if sopt.HTTPSPort != 443 {
+			target = fmt.Sprintf("https://%s:%d", host, sopt.HTTPSPort)
+		}
+		http.Redirect(w, r, target+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
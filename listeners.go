@@ -0,0 +1,86 @@
+package easyhttpserver
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenUnixSocket creates (or re-creates) a Unix domain socket at path,
+// applying the given mode and owner. A stale socket file left behind by a
+// previous crashed process is removed before binding.
+func listenUnixSocket(path string, mode os.FileMode, owner string) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("cannot remove stale unix socket %q: %w", path, err)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen on unix socket %q: %w", path, err)
+	}
+
+	if mode == 0 {
+		mode = 0660
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("cannot chmod unix socket %q: %w", path, err)
+	}
+
+	if owner != "" {
+		if err := chownPath(path, owner); err != nil {
+			ln.Close()
+			return nil, fmt.Errorf("cannot chown unix socket %q: %w", path, err)
+		}
+	}
+
+	return ln, nil
+}
+
+// systemdListeners returns the listeners passed to this process via systemd
+// socket activation (LISTEN_FDS/LISTEN_PID), in file descriptor order, or nil
+// if this process was not socket-activated. See sd_listen_fds(3).
+func systemdListeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	listeners := make([]net.Listener, 0, n)
+	for i := 0; i < n; i++ {
+		fd := uintptr(3 + i)
+		name := "LISTEN_FD_" + strconv.Itoa(int(fd))
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		f := os.NewFile(fd, name)
+		if f == nil {
+			return nil, fmt.Errorf("systemd socket activation: invalid inherited file descriptor %d", fd)
+		}
+
+		ln, err := net.FileListener(f)
+		if err != nil {
+			return nil, fmt.Errorf("systemd socket activation: cannot use inherited file descriptor %d (%s): %w", fd, name, err)
+		}
+		f.Close()
+
+		listeners = append(listeners, ln)
+	}
+
+	// Prevent systemd from re-activating this process while it's already running.
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	return listeners, nil
+}
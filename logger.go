@@ -0,0 +1,45 @@
+package easyhttpserver
+
+// Logger receives informational, warning and error messages, as well as
+// structured lifecycle events, emitted by Server. Use LogPrintf, LogSlog or
+// LogZap to adapt an existing logger, or implement this interface directly.
+//
+// Event names are dot-separated and stable across versions:
+//
+//	listener.started         fields: addr
+//	listener.failed          fields: addr, error
+//	acme.cert_obtained       fields: key
+//	acme.cert_renewed        fields: key
+//	acme.challenge_served    fields: host, path
+//	shutdown.graceful_timeout (no fields)
+//	shutdown.forced           (no fields)
+//	http.request             fields: method, path, status, bytes, duration_ms, remote_addr, request_id
+//	http.panic               fields: method, path, error
+type Logger interface {
+	Infof(format string, v ...interface{})
+	Warnf(format string, v ...interface{})
+	Errorf(format string, v ...interface{})
+	Event(name string, fields map[string]interface{})
+}
+
+// Lifecycle event names emitted via Logger.Event.
+const (
+	EventListenerStarted         = "listener.started"
+	EventListenerFailed          = "listener.failed"
+	EventACMECertObtained        = "acme.cert_obtained"
+	EventACMECertRenewed         = "acme.cert_renewed"
+	EventACMEChallengeServed     = "acme.challenge_served"
+	EventShutdownGracefulTimeout = "shutdown.graceful_timeout"
+	EventShutdownForced          = "shutdown.forced"
+	EventHTTPRequest             = "http.request"
+	EventHTTPPanic               = "http.panic"
+)
+
+// noopLogger discards everything; it is the default when Options.Log is nil,
+// so the rest of the package never needs a nil check.
+type noopLogger struct{}
+
+func (noopLogger) Infof(format string, v ...interface{})            {}
+func (noopLogger) Warnf(format string, v ...interface{})            {}
+func (noopLogger) Errorf(format string, v ...interface{})           {}
+func (noopLogger) Event(name string, fields map[string]interface{}) {}
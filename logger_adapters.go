@@ -0,0 +1,79 @@
+package easyhttpserver
+
+import (
+	"fmt"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+// LogPrintf adapts a log.Printf-style function (such as log.Printf itself)
+// into a Logger. Events are rendered as a single line with space-separated
+// key=value pairs.
+func LogPrintf(printf func(format string, v ...interface{})) Logger {
+	return &printfLogger{printf}
+}
+
+type printfLogger struct {
+	printf func(format string, v ...interface{})
+}
+
+func (l *printfLogger) Infof(format string, v ...interface{})  { l.printf(format, v...) }
+func (l *printfLogger) Warnf(format string, v ...interface{})  { l.printf("WARN: "+format, v...) }
+func (l *printfLogger) Errorf(format string, v ...interface{}) { l.printf("ERROR: "+format, v...) }
+
+func (l *printfLogger) Event(name string, fields map[string]interface{}) {
+	l.printf("%s", formatEvent(name, fields))
+}
+
+func formatEvent(name string, fields map[string]interface{}) string {
+	s := "event: " + name
+	for k, v := range fields {
+		s += fmt.Sprintf(" %s=%v", k, v)
+	}
+	return s
+}
+
+// LogSlog adapts a *slog.Logger into a Logger, emitting lifecycle events via
+// l.Info(name, ...) with fields passed as key-value pairs.
+func LogSlog(l *slog.Logger) Logger {
+	return &slogLogger{l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (l *slogLogger) Infof(format string, v ...interface{})  { l.l.Info(fmt.Sprintf(format, v...)) }
+func (l *slogLogger) Warnf(format string, v ...interface{})  { l.l.Warn(fmt.Sprintf(format, v...)) }
+func (l *slogLogger) Errorf(format string, v ...interface{}) { l.l.Error(fmt.Sprintf(format, v...)) }
+
+func (l *slogLogger) Event(name string, fields map[string]interface{}) {
+	args := make([]any, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	l.l.Info(name, args...)
+}
+
+// LogZap adapts a *zap.SugaredLogger into a Logger, emitting lifecycle events
+// via l.Infow(name, ...) with fields passed as key-value pairs.
+func LogZap(l *zap.SugaredLogger) Logger {
+	return &zapLogger{l}
+}
+
+type zapLogger struct {
+	l *zap.SugaredLogger
+}
+
+func (l *zapLogger) Infof(format string, v ...interface{})  { l.l.Infof(format, v...) }
+func (l *zapLogger) Warnf(format string, v ...interface{})  { l.l.Warnf(format, v...) }
+func (l *zapLogger) Errorf(format string, v ...interface{}) { l.l.Errorf(format, v...) }
+
+func (l *zapLogger) Event(name string, fields map[string]interface{}) {
+	args := make([]interface{}, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	l.l.Infow(name, args...)
+}
@@ -0,0 +1,47 @@
+package easyhttpserver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatEvent(t *testing.T) {
+	got := formatEvent(EventListenerStarted, map[string]interface{}{"addr": ":8080"})
+	want := "event: listener.started addr=:8080"
+	if got != want {
+		t.Errorf("formatEvent() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatEventNoFields(t *testing.T) {
+	got := formatEvent(EventShutdownForced, nil)
+	want := "event: " + EventShutdownForced
+	if got != want {
+		t.Errorf("formatEvent() = %q, want %q", got, want)
+	}
+}
+
+func TestLogPrintfEvent(t *testing.T) {
+	var logged string
+	log := LogPrintf(func(format string, v ...interface{}) {
+		logged = fmt.Sprintf(format, v...)
+	})
+
+	log.Event(EventHTTPPanic, map[string]interface{}{"method": "GET"})
+	if !strings.Contains(logged, "event: "+EventHTTPPanic) || !strings.Contains(logged, "method=GET") {
+		t.Errorf("logged = %q, want it to contain the event name and fields", logged)
+	}
+}
+
+func TestLogPrintfWarnfPrefixesMessage(t *testing.T) {
+	var logged string
+	log := LogPrintf(func(format string, v ...interface{}) {
+		logged = format
+	})
+
+	log.Warnf("disk almost full")
+	if !strings.HasPrefix(logged, "WARN: ") {
+		t.Errorf("logged = %q, want WARN: prefix", logged)
+	}
+}
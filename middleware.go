@@ -0,0 +1,188 @@
+package easyhttpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader is the header the RequestID middleware reads an existing
+// request id from (e.g. one set by a load balancer), generating its own id
+// otherwise, and sets it on the response either way.
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDContextKey struct{}
+
+// RequestIDFromContext returns the request id set by the RequestID
+// middleware, or "" if RequestID wasn't enabled.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, id)))
+	})
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unavailable"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// realIPMiddleware overwrites r.RemoteAddr with the client address found in
+// the X-Forwarded-For or X-Real-IP header. Only enable this behind a trusted
+// reverse proxy, since these headers are otherwise trivially spoofable.
+func realIPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ip := realClientIP(r); ip != "" {
+			r.RemoteAddr = ip
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func realClientIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		if i := strings.IndexByte(xff, ','); i >= 0 {
+			xff = xff[:i]
+		}
+		return strings.TrimSpace(xff)
+	}
+	return strings.TrimSpace(r.Header.Get("X-Real-IP"))
+}
+
+// statusRecorder wraps a ResponseWriter to capture the status code and byte
+// count written, for accessLogMiddleware.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// accessLogMiddleware emits an EventHTTPRequest event, via log, for every
+// request that reaches it, including the final status code even if that
+// status was set further down the chain (e.g. by recoveryMiddleware).
+func accessLogMiddleware(log Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			start := time.Now()
+			next.ServeHTTP(rec, r)
+			status := rec.status
+			if status == 0 {
+				// Nothing was written; net/http sends an implicit 200 OK.
+				status = http.StatusOK
+			}
+			log.Event(EventHTTPRequest, map[string]interface{}{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"status":      status,
+				"bytes":       rec.bytes,
+				"duration_ms": time.Since(start).Milliseconds(),
+				"remote_addr": r.RemoteAddr,
+				"request_id":  RequestIDFromContext(r.Context()),
+			})
+		})
+	}
+}
+
+// recoveryMiddleware recovers panics from the rest of the handler chain,
+// logs them via log, and responds with 500 instead of crashing the process.
+func recoveryMiddleware(log Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if p := recover(); p != nil {
+					log.Errorf("panic handling %s %s: %v", r.Method, r.URL.Path, p)
+					log.Event(EventHTTPPanic, map[string]interface{}{
+						"method": r.Method,
+						"path":   r.URL.Path,
+						"error":  fmt.Sprint(p),
+					})
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// healthMiddleware answers healthPath and readyPath with a fixed 200 OK,
+// short-circuiting the rest of the middleware chain and the user's handler.
+func healthMiddleware(healthPath, readyPath string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+				(r.URL.Path == healthPath || r.URL.Path == readyPath) {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("ok"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// wrapMiddleware wraps handler with sopt's built-in middleware toggles and
+// Options.Middleware, in a fixed order (outermost first): health/ready
+// short-circuit, RequestID, RealIP, AccessLog, Recovery, then
+// Options.Middleware (in slice order), then handler.
+func (sopt Options) wrapMiddleware(handler http.Handler, log Logger) http.Handler {
+	healthPath := sopt.HealthPath
+	if healthPath == "" {
+		healthPath = "/healthz"
+	}
+	readyPath := sopt.ReadyPath
+	if readyPath == "" {
+		readyPath = "/readyz"
+	}
+
+	mws := []func(http.Handler) http.Handler{healthMiddleware(healthPath, readyPath)}
+	if sopt.RequestID {
+		mws = append(mws, requestIDMiddleware)
+	}
+	if sopt.RealIP {
+		mws = append(mws, realIPMiddleware)
+	}
+	if sopt.AccessLog {
+		mws = append(mws, accessLogMiddleware(log))
+	}
+	if sopt.Recovery {
+		mws = append(mws, recoveryMiddleware(log))
+	}
+	mws = append(mws, sopt.Middleware...)
+
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,94 @@
+package easyhttpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWrapMiddlewareHealthShortCircuits(t *testing.T) {
+	called := false
+	handler := Options{}.wrapMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), noopLogger{})
+
+	for _, path := range []string{"/healthz", "/readyz"} {
+		called = false
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200", path, rec.Code)
+		}
+		if called {
+			t.Errorf("%s: handler was called, want health check to short-circuit it", path)
+		}
+	}
+}
+
+func TestWrapMiddlewareCustomHealthPath(t *testing.T) {
+	handler := Options{HealthPath: "/alive"}.wrapMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called for the health path")
+	}), noopLogger{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/alive", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestWrapMiddlewareRequestID(t *testing.T) {
+	var idInHandler string
+	sopt := Options{RequestID: true}
+	handler := sopt.wrapMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idInHandler = RequestIDFromContext(r.Context())
+	}), noopLogger{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if idInHandler == "" {
+		t.Error("RequestIDFromContext returned \"\" inside the handler")
+	}
+	if rec.Header().Get(RequestIDHeader) != idInHandler {
+		t.Errorf("response header %s = %q, want %q", RequestIDHeader, rec.Header().Get(RequestIDHeader), idInHandler)
+	}
+}
+
+func TestWrapMiddlewareRecoveryCatchesPanic(t *testing.T) {
+	sopt := Options{Recovery: true}
+	handler := sopt.wrapMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), noopLogger{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+}
+
+func TestWrapMiddlewareCustomMiddlewareRunsInnermost(t *testing.T) {
+	var order []string
+	custom := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			order = append(order, "custom")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	sopt := Options{RequestID: true, Middleware: []func(http.Handler) http.Handler{custom}}
+	handler := sopt.wrapMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}), noopLogger{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"custom", "handler"}
+	if len(order) != len(want) || order[0] != want[0] || order[1] != want[1] {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,77 @@
+package easyhttpserver
+
+import "testing"
+
+func TestVerifyHTTPBehaviorRequiresHTTPS(t *testing.T) {
+	cases := []HTTPBehavior{HTTPRedirectToHTTPS, HTTPDisabled}
+	for _, behavior := range cases {
+		sopt := Options{Host: "example.com", HTTPBehavior: behavior}
+		if err := sopt.Verify(); err == nil {
+			t.Errorf("Verify() with HTTPBehavior=%v and no HTTPS configured = nil error, want error", behavior)
+		}
+	}
+}
+
+func TestVerifyHTTPAcmeOnlyRequiresLetsEncrypt(t *testing.T) {
+	sopt := Options{Host: "example.com", HTTPBehavior: HTTPAcmeOnly}
+	if err := sopt.Verify(); err == nil {
+		t.Error("Verify() with HTTPAcmeOnly and LetsEncrypt disabled = nil error, want error")
+	}
+}
+
+func TestVerifyLetsEncryptRequiresHTTPListener(t *testing.T) {
+	sopt := Options{
+		Host:                "example.com",
+		LetsEncrypt:         true,
+		LetsEncryptEmail:    "me@example.com",
+		LetsEncryptCacheDir: "/tmp/certs",
+		HTTPBehavior:        HTTPDisabled,
+	}
+	if err := sopt.Verify(); err == nil {
+		t.Error("Verify() with LetsEncrypt and HTTPDisabled = nil error, want error")
+	}
+}
+
+func TestVerifyHTTPPortDefaultsToPort(t *testing.T) {
+	sopt := Options{Host: "example.com", Port: 8080}
+	if err := sopt.Verify(); err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+	if sopt.HTTPPort != 8080 {
+		t.Errorf("HTTPPort = %d, want 8080 (defaulted from Port)", sopt.HTTPPort)
+	}
+}
+
+func TestVerifyHTTPSPortDefaultsTo443(t *testing.T) {
+	sopt := Options{
+		Host:     "example.com",
+		Port:     80,
+		CertFile: "cert.pem",
+		KeyFile:  "key.pem",
+	}
+	if err := sopt.Verify(); err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+	if sopt.HTTPSPort != 443 {
+		t.Errorf("HTTPSPort = %d, want 443", sopt.HTTPSPort)
+	}
+}
+
+func TestVerifyCertFileRequiresKeyFile(t *testing.T) {
+	sopt := Options{Host: "example.com", CertFile: "cert.pem"}
+	if err := sopt.Verify(); err == nil {
+		t.Error("Verify() with CertFile but no KeyFile = nil error, want error")
+	}
+}
+
+func TestVerifyCertFileExclusiveWithLetsEncrypt(t *testing.T) {
+	sopt := Options{
+		Host:        "example.com",
+		CertFile:    "cert.pem",
+		KeyFile:     "key.pem",
+		LetsEncrypt: true,
+	}
+	if err := sopt.Verify(); err == nil {
+		t.Error("Verify() with both CertFile and LetsEncrypt = nil error, want error")
+	}
+}
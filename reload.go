@@ -0,0 +1,276 @@
+package easyhttpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Reload swaps in a new handler and configuration without closing any
+// listeners or dropping in-flight connections, similar to Caddy's
+// zero-downtime config reload. The listening addresses (HTTPPort, HTTPSPort,
+// UnixSocket) must stay the same as what Start or Adopt was called with; only
+// the handler, Let's Encrypt policy (including the set of allowed hosts) and
+// static certificate may change. For a binary upgrade, use ListenerFiles plus
+// an exec-based restart instead; the replacement process Adopts these same
+// listeners, so the listening addresses stay the same that way too.
+func (srv *Server) Reload(handler http.Handler, sopt Options) error {
+	if sopt.PrimaryScheme == "" {
+		if err := sopt.Verify(); err != nil {
+			return err
+		}
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	handler = sopt.wrapMiddleware(handler, srv.log)
+	httpHandler := handler
+
+	switch {
+	case sopt.LetsEncrypt:
+		if srv.mgr == nil {
+			return fmt.Errorf("cannot Reload into LetsEncrypt mode: server was not started with it")
+		}
+
+		cache := sopt.CertCache
+		if cache == nil {
+			cache = autocert.DirCache(sopt.LetsEncryptCacheDir)
+		}
+		cache = &loggingCache{Cache: cache, log: srv.log}
+		hostPolicy := sopt.HostPolicy
+		if hostPolicy == nil {
+			hostPolicy = autocert.HostWhitelist(sopt.Host)
+		}
+		// srv.mgr.Cache and srv.mgr.HostPolicy are autocert.Manager fields
+		// read with no locking from mgr.GetCertificate on every concurrent
+		// TLS handshake, so they can't be written to directly here; swap
+		// them through the atomic indirection set up in wireServer instead.
+		srv.acmeCache.store(cache)
+		srv.acmeHostPolicy.store(hostPolicy)
+		srv.mgr.Email = sopt.LetsEncryptEmail
+
+		var fallback http.Handler
+		if sopt.HTTPBehavior != HTTPRedirectToHTTPS {
+			fallback = httpFallbackHandler(sopt.HTTPBehavior, handler, sopt)
+		}
+		httpHandler = wrapACMEChallengeLogging(srv.mgr.HTTPHandler(fallback), srv.log)
+		srv.httpsHandlerBox.store(handler)
+
+	case sopt.CertFile != "":
+		if srv.certStore == nil {
+			return fmt.Errorf("cannot Reload into CertFile mode: server was not started with it")
+		}
+		if err := srv.certStore.load(sopt.CertFile, sopt.KeyFile); err != nil {
+			return err
+		}
+		httpHandler = httpFallbackHandler(sopt.HTTPBehavior, handler, sopt)
+		srv.httpsHandlerBox.store(handler)
+
+	default:
+		httpHandler = httpFallbackHandler(sopt.HTTPBehavior, handler, sopt)
+		if srv.httpsHandlerBox != nil {
+			srv.httpsHandlerBox.store(handler)
+		}
+	}
+
+	if sopt.H2C {
+		httpHandler = wrapH2C(httpHandler)
+	}
+	srv.httpHandlerBox.store(httpHandler)
+	srv.opts = sopt
+
+	return nil
+}
+
+// ListenerFiles returns duplicated file descriptors for this server's
+// listeners (Unix socket and/or plain HTTP, followed by HTTPS if enabled),
+// suitable for passing to a freshly exec'd replacement process - e.g. via
+// os/exec.Cmd.ExtraFiles, with EASYHTTP_INHERITED_FDS set to the returned
+// count - so that process can Adopt them without ever closing the listening
+// sockets. Closing the returned files does not affect the running server.
+func (srv *Server) ListenerFiles() ([]*os.File, error) {
+	var files []*os.File
+	for _, ln := range srv.rawListeners {
+		f, err := listenerFile(ln)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if srv.rawHTTPSListener != nil {
+		f, err := listenerFile(srv.rawHTTPSListener)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func listenerFile(ln net.Listener) (*os.File, error) {
+	type filer interface {
+		File() (*os.File, error)
+	}
+	f, ok := ln.(filer)
+	if !ok {
+		return nil, fmt.Errorf("listener %T does not support inheriting its file descriptor", ln)
+	}
+	return f.File()
+}
+
+// InheritedListenerFiles returns the listener file descriptors passed to this
+// process for a zero-downtime binary upgrade, as set by the
+// EASYHTTP_INHERITED_FDS environment variable (a count), with the files
+// themselves passed via os/exec.Cmd.ExtraFiles starting at fd 3. Pass the
+// result to Adopt. Returns nil if EASYHTTP_INHERITED_FDS is not set.
+func InheritedListenerFiles() []*os.File {
+	n, err := strconv.Atoi(os.Getenv("EASYHTTP_INHERITED_FDS"))
+	if err != nil || n <= 0 {
+		return nil
+	}
+
+	files := make([]*os.File, n)
+	for i := 0; i < n; i++ {
+		fd := uintptr(3 + i)
+		files[i] = os.NewFile(fd, fmt.Sprintf("inherited-fd-%d", fd))
+	}
+	return files
+}
+
+// Adopt reconstructs a Server from listener files inherited across a binary
+// upgrade (see ListenerFiles and InheritedListenerFiles), instead of opening
+// new listeners of its own. This lets a freshly exec'd replacement process
+// start accepting connections without the old process ever closing its
+// sockets. files must be in the order ListenerFiles returned them in: the
+// plain HTTP listeners (Unix socket and/or HTTPPort), followed by the HTTPS
+// listener if HTTPS is enabled.
+func Adopt(handler http.Handler, files []*os.File, sopt Options) (*Server, error) {
+	if sopt.PrimaryScheme == "" {
+		if err := sopt.Verify(); err != nil {
+			return nil, err
+		}
+	}
+
+	listeners := make([]net.Listener, 0, len(files))
+	for _, f := range files {
+		ln, err := net.FileListener(f)
+		if err != nil {
+			return nil, fmt.Errorf("cannot adopt inherited listener %q: %w", f.Name(), err)
+		}
+		f.Close()
+		listeners = append(listeners, ln)
+	}
+
+	httpsEnabled := sopt.LetsEncrypt || sopt.CertFile != ""
+	httpListeners := listeners
+	var httpsListener net.Listener
+	if httpsEnabled && len(listeners) > 0 {
+		httpListeners = listeners[:len(listeners)-1]
+		httpsListener = listeners[len(listeners)-1]
+	}
+
+	return wireServer(handler, sopt, httpListeners, httpsListener)
+}
+
+// reloadableHandler lets Reload swap the active http.Handler without a data
+// race against in-flight requests reading srv.Handler.
+type reloadableHandler struct {
+	v atomic.Value // http.Handler
+}
+
+func newReloadableHandler(h http.Handler) *reloadableHandler {
+	rh := &reloadableHandler{}
+	rh.store(h)
+	return rh
+}
+
+func (rh *reloadableHandler) store(h http.Handler) {
+	rh.v.Store(&h)
+}
+
+func (rh *reloadableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := *rh.v.Load().(*http.Handler)
+	h.ServeHTTP(w, r)
+}
+
+// atomicCertificate lets Reload hot-swap a static CertFile/KeyFile pair
+// without restarting the HTTPS listener.
+type atomicCertificate struct {
+	v atomic.Value // *tls.Certificate
+}
+
+func (c *atomicCertificate) load(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("cannot load certificate %q / %q: %w", certFile, keyFile, err)
+	}
+	c.v.Store(&cert)
+	return nil
+}
+
+func (c *atomicCertificate) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.v.Load().(*tls.Certificate), nil
+}
+
+// acmeCache implements autocert.Cache on top of an atomically swappable
+// autocert.Cache, letting Reload change where ACME state is stored without
+// racing autocert.Manager's own concurrent Get/Put calls during in-flight
+// handshakes and renewals.
+type acmeCache struct {
+	v atomic.Value // autocert.Cache
+}
+
+func newACMECache(cache autocert.Cache) *acmeCache {
+	c := &acmeCache{}
+	c.store(cache)
+	return c
+}
+
+func (c *acmeCache) store(cache autocert.Cache) {
+	c.v.Store(&cache)
+}
+
+func (c *acmeCache) current() autocert.Cache {
+	return *c.v.Load().(*autocert.Cache)
+}
+
+func (c *acmeCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return c.current().Get(ctx, key)
+}
+
+func (c *acmeCache) Put(ctx context.Context, key string, data []byte) error {
+	return c.current().Put(ctx, key, data)
+}
+
+func (c *acmeCache) Delete(ctx context.Context, key string) error {
+	return c.current().Delete(ctx, key)
+}
+
+// acmeHostPolicy wraps an atomically swappable autocert.HostPolicy, letting
+// Reload change the allowed-host set without racing
+// autocert.Manager.GetCertificate's concurrent reads of it.
+type acmeHostPolicy struct {
+	v atomic.Value // autocert.HostPolicy
+}
+
+func newACMEHostPolicy(policy autocert.HostPolicy) *acmeHostPolicy {
+	p := &acmeHostPolicy{}
+	p.store(policy)
+	return p
+}
+
+func (p *acmeHostPolicy) store(policy autocert.HostPolicy) {
+	p.v.Store(&policy)
+}
+
+func (p *acmeHostPolicy) policy(ctx context.Context, host string) error {
+	return (*p.v.Load().(*autocert.HostPolicy))(ctx, host)
+}
@@ -0,0 +1,89 @@
+package easyhttpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// memCache is a minimal in-memory autocert.Cache for tests.
+type memCache struct{}
+
+func (memCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, autocert.ErrCacheMiss
+}
+func (memCache) Put(ctx context.Context, key string, data []byte) error { return nil }
+func (memCache) Delete(ctx context.Context, key string) error           { return nil }
+
+// TestACMECacheStoreRace exercises acmeCache.store concurrently with Get/Put,
+// the same pattern Reload uses against mgr.GetCertificate's concurrent
+// handshake reads. Run with -race to catch a regression to direct
+// autocert.Manager.Cache field writes.
+func TestACMECacheStoreRace(t *testing.T) {
+	cache := newACMECache(memCache{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			cache.Get(context.Background(), "k")
+			cache.Put(context.Background(), "k", nil)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			cache.store(memCache{})
+		}
+	}()
+	wg.Wait()
+}
+
+// TestACMEHostPolicyStoreRace is the HostPolicy analogue of
+// TestACMECacheStoreRace.
+func TestACMEHostPolicyStoreRace(t *testing.T) {
+	policy := newACMEHostPolicy(autocert.HostWhitelist("a.example.com"))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			policy.policy(context.Background(), "a.example.com")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			policy.store(autocert.HostWhitelist("b.example.com"))
+		}
+	}()
+	wg.Wait()
+}
+
+func TestReloadableHandlerSwap(t *testing.T) {
+	h := newReloadableHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("old"))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Body.String() != "old" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "old")
+	}
+
+	h.store(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("new"))
+	}))
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Body.String() != "new" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "new")
+	}
+}
@@ -20,3 +20,32 @@ func InterceptShutdownSignals(shutdown func()) {
 		shutdown()
 	}()
 }
+
+// InterceptReloadSignals invokes reload every time SIGUSR1 is received, and
+// restart every time SIGUSR2 is received, until InterceptShutdownSignals
+// handles a shutdown signal, which resets all signal handling, this included.
+// Typical usage is reload calling Server.Reload to pick up new configuration
+// in place, and restart handing off to a freshly exec'd replacement process
+// via Server.ListenerFiles. Either callback may be nil to ignore that signal.
+// Unlike InterceptShutdownSignals, this fires repeatedly and does not affect
+// the shutdown signal handling set up separately.
+func InterceptReloadSignals(reload func(), restart func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for sig := range c {
+			switch sig {
+			case syscall.SIGUSR1:
+				if reload != nil {
+					log.Println("reloading configuration")
+					reload()
+				}
+			case syscall.SIGUSR2:
+				if restart != nil {
+					log.Println("restarting")
+					restart()
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,74 @@
+package easyhttpserver
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/netutil"
+)
+
+const (
+	defaultReadTimeout       = 60 * time.Second
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultMaxHeaderBytes    = 1 << 20 // 1 MB
+	defaultKeepAlivePeriod   = 3 * time.Minute
+)
+
+// applyTimeouts fills in srv's timeout and header size fields from sopt,
+// falling back to sane defaults for anything left at zero. Without these,
+// http.Server has no limits at all, which is a well-known footgun (slowloris,
+// resource exhaustion).
+func (sopt Options) applyTimeouts(srv *http.Server) {
+	srv.ReadTimeout = durationOrDefault(sopt.ReadTimeout, defaultReadTimeout)
+	srv.ReadHeaderTimeout = durationOrDefault(sopt.ReadHeaderTimeout, defaultReadHeaderTimeout)
+	srv.WriteTimeout = durationOrDefault(sopt.WriteTimeout, defaultWriteTimeout)
+	srv.IdleTimeout = durationOrDefault(sopt.IdleTimeout, defaultIdleTimeout)
+	if sopt.MaxHeaderBytes != 0 {
+		srv.MaxHeaderBytes = sopt.MaxHeaderBytes
+	} else {
+		srv.MaxHeaderBytes = defaultMaxHeaderBytes
+	}
+}
+
+func durationOrDefault(v, def time.Duration) time.Duration {
+	if v != 0 {
+		return v
+	}
+	return def
+}
+
+// wrapListener applies MaxConnections and KeepAlivePeriod to ln.
+func (sopt Options) wrapListener(ln net.Listener) net.Listener {
+	ln = &keepAliveListener{
+		Listener: ln,
+		period:   durationOrDefault(sopt.KeepAlivePeriod, defaultKeepAlivePeriod),
+	}
+	if sopt.MaxConnections > 0 {
+		ln = netutil.LimitListener(ln, sopt.MaxConnections)
+	}
+	return ln
+}
+
+// keepAliveListener sets a TCP keep-alive period on every accepted
+// connection, the way net/http.Server.ListenAndServe does internally (with a
+// hardcoded 3-minute period) when it creates its own listener. We lose that
+// behavior by calling Serve with our own listener, so we reimplement it here.
+type keepAliveListener struct {
+	net.Listener
+	period time.Duration
+}
+
+func (l *keepAliveListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if tc, ok := c.(*net.TCPConn); ok {
+		tc.SetKeepAlive(true)
+		tc.SetKeepAlivePeriod(l.period)
+	}
+	return c, nil
+}
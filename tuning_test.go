@@ -0,0 +1,58 @@
+package easyhttpserver
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestApplyTimeoutsDefaults(t *testing.T) {
+	var sopt Options
+	srv := &http.Server{}
+	sopt.applyTimeouts(srv)
+
+	if srv.ReadTimeout != defaultReadTimeout {
+		t.Errorf("ReadTimeout = %v, want %v", srv.ReadTimeout, defaultReadTimeout)
+	}
+	if srv.ReadHeaderTimeout != defaultReadHeaderTimeout {
+		t.Errorf("ReadHeaderTimeout = %v, want %v", srv.ReadHeaderTimeout, defaultReadHeaderTimeout)
+	}
+	if srv.WriteTimeout != defaultWriteTimeout {
+		t.Errorf("WriteTimeout = %v, want %v", srv.WriteTimeout, defaultWriteTimeout)
+	}
+	if srv.IdleTimeout != defaultIdleTimeout {
+		t.Errorf("IdleTimeout = %v, want %v", srv.IdleTimeout, defaultIdleTimeout)
+	}
+	if srv.MaxHeaderBytes != defaultMaxHeaderBytes {
+		t.Errorf("MaxHeaderBytes = %d, want %d", srv.MaxHeaderBytes, defaultMaxHeaderBytes)
+	}
+}
+
+func TestApplyTimeoutsOverrides(t *testing.T) {
+	sopt := Options{
+		ReadTimeout:    5 * time.Second,
+		MaxHeaderBytes: 4096,
+	}
+	srv := &http.Server{}
+	sopt.applyTimeouts(srv)
+
+	if srv.ReadTimeout != 5*time.Second {
+		t.Errorf("ReadTimeout = %v, want 5s", srv.ReadTimeout)
+	}
+	if srv.MaxHeaderBytes != 4096 {
+		t.Errorf("MaxHeaderBytes = %d, want 4096", srv.MaxHeaderBytes)
+	}
+	// Fields left unset still fall back to their defaults.
+	if srv.WriteTimeout != defaultWriteTimeout {
+		t.Errorf("WriteTimeout = %v, want %v", srv.WriteTimeout, defaultWriteTimeout)
+	}
+}
+
+func TestDurationOrDefault(t *testing.T) {
+	if got := durationOrDefault(0, time.Minute); got != time.Minute {
+		t.Errorf("durationOrDefault(0, 1m) = %v, want 1m", got)
+	}
+	if got := durationOrDefault(5*time.Second, time.Minute); got != 5*time.Second {
+		t.Errorf("durationOrDefault(5s, 1m) = %v, want 5s", got)
+	}
+}
@@ -0,0 +1,52 @@
+package easyhttpserver
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// chownPath changes the owner of path to the given user[:group] spec, e.g.
+// "www-data" or "www-data:www-data". Numeric uid[:gid] is also accepted.
+func chownPath(path string, owner string) error {
+	userName, groupName, _ := strings.Cut(owner, ":")
+
+	uid, err := lookupUID(userName)
+	if err != nil {
+		return err
+	}
+
+	gid := -1
+	if groupName != "" {
+		gid, err = lookupGID(groupName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return syscall.Chown(path, uid, gid)
+}
+
+func lookupUID(name string) (int, error) {
+	if uid, err := strconv.Atoi(name); err == nil {
+		return uid, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, fmt.Errorf("unknown user %q: %w", name, err)
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+func lookupGID(name string) (int, error) {
+	if gid, err := strconv.Atoi(name); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, fmt.Errorf("unknown group %q: %w", name, err)
+	}
+	return strconv.Atoi(g.Gid)
+}
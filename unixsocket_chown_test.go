@@ -0,0 +1,39 @@
+package easyhttpserver
+
+import (
+	"os/user"
+	"strconv"
+	"testing"
+)
+
+func TestLookupUIDNumeric(t *testing.T) {
+	uid, err := lookupUID("0")
+	if err != nil {
+		t.Fatalf("lookupUID(\"0\"): %v", err)
+	}
+	if uid != 0 {
+		t.Errorf("uid = %d, want 0", uid)
+	}
+}
+
+func TestLookupUIDByName(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Skipf("user.Current: %v", err)
+	}
+
+	uid, err := lookupUID(u.Username)
+	if err != nil {
+		t.Fatalf("lookupUID(%q): %v", u.Username, err)
+	}
+	want, _ := strconv.Atoi(u.Uid)
+	if uid != want {
+		t.Errorf("uid = %d, want %d", uid, want)
+	}
+}
+
+func TestLookupUIDUnknown(t *testing.T) {
+	if _, err := lookupUID("no-such-user-xyz"); err == nil {
+		t.Error("expected an error for an unknown user")
+	}
+}